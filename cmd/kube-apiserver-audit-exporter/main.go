@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log/slog"
 	"os"
 	"strings"
@@ -11,11 +12,22 @@ import (
 )
 
 var (
-	auditLogPath = []string{"./audit.log"}
-	address      = ":8080"
-	cluster      = ""
-	replay       = false
-	delay        time.Duration
+	auditLogPath       = []string{"./audit.log"}
+	address            = ":8080"
+	cluster            = ""
+	replay             = false
+	replaySpeed        = 1.0
+	delay              time.Duration
+	pendingTTL         = time.Hour
+	maxPending         = 100_000
+	mode               = "file"
+	webhookAddress     = ":8443"
+	webhookBearerToken = ""
+	maxAnomalyTuples   = 50_000
+	anomalyThreshold   = 3.0
+	otlpEndpoint       = ""
+	jsonSinkPath       = ""
+	webhookSinkURL     = ""
 )
 
 func init() {
@@ -23,10 +35,51 @@ func init() {
 	pflag.StringVar(&address, "address", address, "Address to listen on")
 	pflag.StringVar(&cluster, "cluster-label", cluster, "Default cluster label of metrics")
 	pflag.BoolVar(&replay, "replay", replay, "replay the audit log")
+	pflag.Float64Var(&replaySpeed, "replay-speed", replaySpeed, "playback speed multiplier when replaying, e.g. 10 for 10x")
 	pflag.DurationVar(&delay, "delay", 0, "delay to start")
+	pflag.DurationVar(&pendingTTL, "pending-ttl", pendingTTL, "how long to keep pending resource state for an object with no matching ready/delete event")
+	pflag.IntVar(&maxPending, "max-pending", maxPending, "maximum number of pending resource states to keep at once")
+	pflag.StringVar(&mode, "mode", mode, "ingestion mode: file (tail audit log files) or webhook (serve a dynamic audit webhook backend)")
+	pflag.StringVar(&webhookAddress, "webhook-address", webhookAddress, "address for the audit webhook server to listen on, in webhook mode")
+	pflag.StringVar(&webhookBearerToken, "webhook-bearer-token", webhookBearerToken, "bearer token required on incoming webhook requests, in webhook mode")
+	pflag.IntVar(&maxAnomalyTuples, "max-anomaly-tuples", maxAnomalyTuples, "maximum number of user/verb/resource tuples to track for rate-limit and anomaly metrics")
+	pflag.Float64Var(&anomalyThreshold, "anomaly-threshold", anomalyThreshold, "standard deviations from baseline before a request rate counts as anomalous")
+	pflag.StringVar(&otlpEndpoint, "otlp-endpoint", otlpEndpoint, "OTLP/gRPC endpoint to additionally export api_requests_total to, e.g. otel-collector:4317")
+	pflag.StringVar(&jsonSinkPath, "json-sink-path", jsonSinkPath, "file to additionally append every processed audit event to, as newline-delimited JSON")
+	pflag.StringVar(&webhookSinkURL, "webhook-sink-url", webhookSinkURL, "URL to additionally POST every processed audit event to")
 	pflag.Parse()
 }
 
+// sinkOptions builds a WithSink option for every additional sink requested
+// on the command line, on top of the always-installed Prometheus sink.
+func sinkOptions() []exporter.Option {
+	var opts []exporter.Option
+
+	if jsonSinkPath != "" {
+		f, err := os.OpenFile(jsonSinkPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			slog.Error("Failed to open JSON sink file", "path", jsonSinkPath, "err", err)
+			os.Exit(1)
+		}
+		opts = append(opts, exporter.WithSink(exporter.NewJSONSink(f)))
+	}
+
+	if webhookSinkURL != "" {
+		opts = append(opts, exporter.WithSink(exporter.NewWebhookSink(webhookSinkURL)))
+	}
+
+	if otlpEndpoint != "" {
+		sink, err := exporter.NewOTLPSink(context.Background(), otlpEndpoint)
+		if err != nil {
+			slog.Error("Failed to create OTLP sink", "endpoint", otlpEndpoint, "err", err)
+			os.Exit(1)
+		}
+		opts = append(opts, exporter.WithSink(sink))
+	}
+
+	return opts
+}
+
 func monitorAndStartExporters() {
 	paths := make([]string, 0, len(auditLogPath))
 	labels := make([]string, 0, len(auditLogPath))
@@ -45,12 +98,21 @@ func monitorAndStartExporters() {
 		time.Sleep(delay)
 	}
 
+	sinks := sinkOptions()
+
 	for i, path := range paths {
-		e := exporter.NewExporter(
+		opts := append([]exporter.Option{
 			exporter.WithReplay(replay),
+			exporter.WithReplaySpeed(replaySpeed),
 			exporter.WithFile(path),
 			exporter.WithClusterLabel(labels[i]),
-		)
+			exporter.WithPendingTTL(pendingTTL),
+			exporter.WithMaxPending(maxPending),
+			exporter.WithMaxAnomalyTuples(maxAnomalyTuples),
+			exporter.WithAnomalyThreshold(anomalyThreshold),
+		}, sinks...)
+
+		e := exporter.NewExporter(opts...)
 		go e.Run()
 	}
 }
@@ -80,7 +142,41 @@ func getPathAndLabel(s string) (string, string) {
 	return path, clusterLabel
 }
 
+func runWebhookMode() {
+	opts := append([]exporter.Option{
+		exporter.WithClusterLabel(cluster),
+		exporter.WithPendingTTL(pendingTTL),
+		exporter.WithMaxPending(maxPending),
+		exporter.WithMaxAnomalyTuples(maxAnomalyTuples),
+		exporter.WithAnomalyThreshold(anomalyThreshold),
+	}, sinkOptions()...)
+
+	e := exporter.NewExporter(opts...)
+
+	webhookOpts := []exporter.WebhookOption{}
+	if webhookBearerToken != "" {
+		webhookOpts = append(webhookOpts, exporter.WithWebhookBearerToken(webhookBearerToken))
+	}
+
+	go func() {
+		if err := exporter.ListenAndServe(address); err != nil {
+			slog.Error("Failed to start metrics server", "err", err)
+			os.Exit(1)
+		}
+	}()
+
+	if err := e.ServeWebhook(webhookAddress, webhookOpts...); err != nil {
+		slog.Error("Failed to start webhook server", "err", err)
+		os.Exit(1)
+	}
+}
+
 func main() {
+	if mode == "webhook" {
+		runWebhookMode()
+		return
+	}
+
 	go monitorAndStartExporters()
 
 	if err := exporter.ListenAndServe(address); err != nil {
@@ -0,0 +1,152 @@
+package exporter
+
+import (
+	"container/list"
+	"math"
+	"sync"
+	"time"
+)
+
+const windowBuckets = 60
+
+// rateKey identifies a (user, verb, resource) tuple tracked for rate-limit
+// and anomaly metrics.
+type rateKey struct {
+	User     string
+	Verb     string
+	Resource string
+}
+
+// slidingWindow counts requests for a single tuple over the trailing 60
+// one-second buckets, and maintains an EWMA baseline of its per-minute rate
+// so that a sudden spike can be scored against what is normal for it.
+type slidingWindow struct {
+	buckets   [windowBuckets]int
+	bucketSec int64
+	baseline  float64
+	variance  float64
+	baselined bool
+}
+
+// ewmaAlpha is the smoothing factor for the baseline rate and variance.
+// A small value means a single noisy minute barely moves the baseline, so a
+// spike still scores as anomalous instead of being absorbed into "normal".
+const ewmaAlpha = 0.1
+
+// observe records one request at now, advancing the window past any
+// buckets that have since gone stale, and returns the requests-per-minute
+// rate together with how many standard deviations it is from the tuple's
+// baseline.
+func (w *slidingWindow) observe(now time.Time) (rpm, zScore float64) {
+	sec := now.Unix()
+	w.advance(sec)
+
+	w.buckets[sec%windowBuckets]++
+
+	var total int
+	for _, c := range w.buckets {
+		total += c
+	}
+	rpm = float64(total)
+
+	if !w.baselined {
+		w.baseline = rpm
+		w.variance = 0
+		w.baselined = true
+		return rpm, 0
+	}
+
+	diff := rpm - w.baseline
+	incr := ewmaAlpha * diff
+	w.baseline += incr
+	w.variance = (1 - ewmaAlpha) * (w.variance + diff*incr)
+
+	stddev := math.Sqrt(w.variance)
+	if stddev == 0 {
+		return rpm, 0
+	}
+	return rpm, diff / stddev
+}
+
+// advance zeroes out any bucket that has fallen out of the trailing window
+// since it was last written, e.g. because the tuple went quiet for a while.
+func (w *slidingWindow) advance(sec int64) {
+	if w.bucketSec == 0 {
+		w.bucketSec = sec
+		return
+	}
+
+	elapsed := sec - w.bucketSec
+	if elapsed <= 0 {
+		return
+	}
+	if elapsed > windowBuckets {
+		elapsed = windowBuckets
+	}
+	for i := int64(1); i <= elapsed; i++ {
+		w.buckets[(w.bucketSec+i)%windowBuckets] = 0
+	}
+	w.bucketSec = sec
+}
+
+// anomalyTracker keeps one slidingWindow per active (user, verb, resource)
+// tuple, bounded to maxSize tuples via LRU eviction so that an unbounded
+// variety of users or resources can't grow it without limit.
+type anomalyTracker struct {
+	maxSize int
+
+	mu    sync.Mutex
+	ll    *list.List
+	items map[rateKey]*list.Element
+}
+
+type anomalyEntry struct {
+	key    rateKey
+	window *slidingWindow
+}
+
+func newAnomalyTracker(maxSize int) *anomalyTracker {
+	return &anomalyTracker{
+		maxSize: maxSize,
+		ll:      list.New(),
+		items:   map[rateKey]*list.Element{},
+	}
+}
+
+// observe records a request for key at now and returns its current
+// requests-per-minute rate and anomaly z-score.
+func (t *anomalyTracker) observe(key rateKey, now time.Time) (rpm, zScore float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	elem, ok := t.items[key]
+	if ok {
+		t.ll.MoveToFront(elem)
+	} else {
+		if t.maxSize > 0 && len(t.items) >= t.maxSize {
+			if oldest := t.ll.Back(); oldest != nil {
+				t.ll.Remove(oldest)
+				delete(t.items, oldest.Value.(*anomalyEntry).key)
+			}
+		}
+		entry := &anomalyEntry{key: key, window: &slidingWindow{}}
+		elem = t.ll.PushFront(entry)
+		t.items[key] = elem
+	}
+
+	return elem.Value.(*anomalyEntry).window.observe(now)
+}
+
+// recordAnomaly updates the per-tuple rate and anomaly-score gauges for a
+// completed request, and increments the anomaly counter once its z-score
+// crosses the configured threshold.
+func (p *Exporter) recordAnomaly(clusterLabel, user, verb, resource string, at time.Time) {
+	rpm, z := p.anomaly.observe(rateKey{User: user, Verb: verb, Resource: resource}, at)
+
+	requestsPerMinute.WithLabelValues(clusterLabel, user, verb, resource).Set(rpm)
+	requestAnomalyScore.WithLabelValues(clusterLabel, user, verb, resource).Set(z)
+
+	if math.Abs(z) >= p.anomalyThreshold {
+		anomalyDetectedTotal.WithLabelValues(clusterLabel, user, verb, resource).Inc()
+	}
+}
@@ -0,0 +1,227 @@
+package exporter
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// fileInode returns the inode number backing info, if the platform exposes
+// one. It is used to tell apart a rotated-and-recreated file from the one
+// the exporter was previously reading, even though both share a path.
+func fileInode(info os.FileInfo) (uint64, bool) {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, false
+	}
+	return st.Ino, true
+}
+
+// maxRotationLookback bounds how many of the most recent rotated siblings
+// drainRotated will stat looking for oldInode, so a directory retaining a
+// long rotation history doesn't make every rotation pay for a full scan.
+const maxRotationLookback = 20
+
+// drainRotated reads whatever is left of the file that used to live at
+// path before rotation, identified by oldInode, starting from offset, plus
+// the full contents of any further rotations that happened before we next
+// looked (e.g. two rotations landing inside one poll tick under high audit
+// log churn). Kube apiservers rotate audit logs by renaming the current
+// file aside (e.g. audit.log -> audit.log.1), so the tail written between
+// our last read and the rotation would otherwise be lost.
+func (p *Exporter) drainRotated(path string, oldInode uint64, offset int64) error {
+	archives := rotatedArchives(path)
+	if len(archives) == 0 {
+		slog.Warn("Audit log rotated but no rotated sibling was found to drain; trailing events may be lost", "cluster", p.clusterLabel, "path", path)
+		return nil
+	}
+
+	matchIdx := -1
+	lookback := len(archives)
+	if lookback > maxRotationLookback {
+		lookback = maxRotationLookback
+	}
+	for i := len(archives) - 1; i >= len(archives)-lookback; i-- {
+		info, err := os.Stat(archives[i])
+		if err != nil {
+			continue
+		}
+		if inode, ok := fileInode(info); ok && inode == oldInode {
+			matchIdx = i
+			break
+		}
+	}
+
+	if matchIdx == -1 {
+		slog.Warn("Could not locate the rotated file matching the previous inode within the last rotations searched; draining all available rotated siblings from the start, the unread tail of the previous file may be lost", "cluster", p.clusterLabel, "path", path, "inode", oldInode, "lookback", lookback)
+		matchIdx = 0
+		offset = 0
+	}
+
+	for i := matchIdx; i < len(archives); i++ {
+		start := int64(0)
+		if i == matchIdx {
+			start = offset
+		}
+		if err := p.drainArchiveFrom(archives[i], start); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// drainArchiveFrom reads archive (transparently decompressing it if
+// needed) starting at byte offset through to EOF, feeding every event it
+// finds into the sinks.
+func (p *Exporter) drainArchiveFrom(archive string, offset int64) error {
+	rc, err := openMaybeCompressed(archive)
+	if err != nil {
+		return fmt.Errorf("failed to open rotated file %s: %w", archive, err)
+	}
+	defer rc.Close()
+
+	if offset > 0 {
+		if seeker, ok := rc.(io.Seeker); ok {
+			if _, err := seeker.Seek(offset, io.SeekStart); err != nil {
+				return fmt.Errorf("seek in rotated file %s failed: %w", archive, err)
+			}
+		} else {
+			// Compressed streams never support io.Seeker. Discard up to the
+			// saved (decompressed-equivalent) offset instead of draining
+			// from byte 0, which would re-feed already-consumed events into
+			// the sinks a second time.
+			if _, err := io.CopyN(io.Discard, rc, offset); err != nil && err != io.EOF {
+				return fmt.Errorf("skipping to offset %d in rotated file %s failed: %w", offset, archive, err)
+			}
+		}
+	}
+
+	reader := bufio.NewReaderSize(rc, 1<<20)
+	if _, err := p.consumeEvents(reader); err != nil {
+		return fmt.Errorf("failed to drain rotated file %s: %w", archive, err)
+	}
+	return nil
+}
+
+var rotatedArchiveRE = regexp.MustCompile(`^\.(\d+)(\.gz|\.zst)?$`)
+
+// replayRotatedArchives drains every already-rotated sibling of path, oldest
+// first, before the caller starts tailing the live file. This lets --replay
+// consume a whole directory of rotated (and optionally compressed) audit
+// logs in the order they were originally written.
+func (p *Exporter) replayRotatedArchives(path string) {
+	for _, archive := range rotatedArchives(path) {
+		rc, err := openMaybeCompressed(archive)
+		if err != nil {
+			slog.Error("Failed to open rotated archive for replay", "cluster", p.clusterLabel, "path", archive, "error", err)
+			continue
+		}
+
+		reader := bufio.NewReaderSize(rc, 1<<20)
+		if _, err := p.consumeEvents(reader); err != nil {
+			slog.Error("Failed to replay rotated archive", "cluster", p.clusterLabel, "path", archive, "error", err)
+		}
+		rc.Close()
+	}
+}
+
+// rotatedArchives finds every sibling of path named "<base>.N" or
+// "<base>.N.gz"/"<base>.N.zst" and returns their paths ordered oldest
+// first, i.e. by descending rotation index, which is how logrotate numbers
+// them (.1 is the most recently rotated).
+func rotatedArchives(path string) []string {
+	dir := filepath.Dir(path)
+	base := filepath.Base(path)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	type archive struct {
+		index int
+		path  string
+	}
+	var found []archive
+
+	for _, ent := range entries {
+		if ent.IsDir() || !strings.HasPrefix(ent.Name(), base+".") {
+			continue
+		}
+
+		m := rotatedArchiveRE.FindStringSubmatch(ent.Name()[len(base):])
+		if m == nil {
+			continue
+		}
+		index, err := strconv.Atoi(m[1])
+		if err != nil {
+			continue
+		}
+
+		found = append(found, archive{index: index, path: filepath.Join(dir, ent.Name())})
+	}
+
+	sort.Slice(found, func(i, j int) bool { return found[i].index > found[j].index })
+
+	paths := make([]string, len(found))
+	for i, a := range found {
+		paths[i] = a.path
+	}
+	return paths
+}
+
+// openMaybeCompressed opens path, transparently decompressing it if its
+// name ends in .gz or .zst.
+func openMaybeCompressed(path string) (io.ReadCloser, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch filepath.Ext(path) {
+	case ".gz":
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+		return readCloser{Reader: gz, closers: []io.Closer{gz, file}}, nil
+	case ".zst":
+		zr, err := zstd.NewReader(file)
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+		return readCloser{Reader: zr.IOReadCloser(), closers: []io.Closer{zr.IOReadCloser(), file}}, nil
+	default:
+		return file, nil
+	}
+}
+
+// readCloser combines a decompressing Reader with the underlying file(s)
+// that must also be closed once it is done with.
+type readCloser struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (r readCloser) Close() error {
+	var err error
+	for _, c := range r.closers {
+		if cerr := c.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
@@ -0,0 +1,63 @@
+package exporter
+
+import "time"
+
+// clock abstracts the exporter's notion of "now" so that replay mode can
+// drive it with simulated time instead of the wall clock. Live (non-replay)
+// mode always uses realClock.
+type clock interface {
+	Now() time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+// virtualClock maps wall-clock elapsed time back onto the replayed event
+// timeline, scaled by speed. It is anchored the moment replay begins: an
+// event occurring at eventStart should appear to happen at wallStart, and an
+// event occurring speed seconds later in the log should appear to happen
+// one wall-clock second later.
+type virtualClock struct {
+	wallStart  time.Time
+	eventStart time.Time
+	speed      float64
+}
+
+func (c *virtualClock) Now() time.Time {
+	elapsed := time.Since(c.wallStart)
+	return c.eventStart.Add(time.Duration(float64(elapsed) * c.speed))
+}
+
+// wallTimeFor returns the wall-clock instant at which eventTime should be
+// delivered, given this clock's anchor and speed.
+func (c *virtualClock) wallTimeFor(eventTime time.Time) time.Time {
+	return c.wallStart.Add(time.Duration(float64(eventTime.Sub(c.eventStart)) / c.speed))
+}
+
+// exporterClock forwards to whatever clock p currently holds, so that
+// long-lived consumers such as pendingCache keep working after replay mode
+// installs a virtualClock partway through the exporter's lifetime.
+type exporterClock struct {
+	p *Exporter
+}
+
+func (c exporterClock) Now() time.Time { return c.p.currentClock().Now() }
+
+// clockHolder lets p.clock be swapped atomically: pace (called from the
+// reader goroutine) installs a virtualClock the first time an event is
+// paced, while runPendingSweep (its own goroutine, running for the
+// exporter's whole lifetime) concurrently reads it through exporterClock.
+type clockHolder struct {
+	clock clock
+}
+
+// setClock atomically installs c as the exporter's current clock.
+func (p *Exporter) setClock(c clock) {
+	p.clockRef.Store(&clockHolder{clock: c})
+}
+
+// currentClock returns the exporter's current clock.
+func (p *Exporter) currentClock() clock {
+	return p.clockRef.Load().clock
+}
@@ -9,6 +9,8 @@ import (
 	"log/slog"
 	"net/http"
 	"os"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -29,35 +31,142 @@ func WithReplay(replay bool) Option {
 	}
 }
 
+// WithReplaySpeed sets the playback speed multiplier used in replay mode,
+// e.g. 10 replays the log ten times faster than it was originally recorded.
+// It has no effect outside of replay mode. The default is 1 (real-time).
+func WithReplaySpeed(speed float64) Option {
+	return func(e *Exporter) {
+		e.replaySpeed = speed
+	}
+}
+
+// WithReplayStart anchors replay pacing to t instead of the timestamp of the
+// first event read, so that multiple exporters replaying different files
+// can be kept in sync with each other.
+func WithReplayStart(t time.Time) Option {
+	return func(e *Exporter) {
+		e.replayStart = t
+	}
+}
+
 func WithClusterLabel(c string) Option {
 	return func(e *Exporter) {
 		e.clusterLabel = c
 	}
 }
 
+const (
+	defaultPendingTTL = time.Hour
+	defaultMaxPending = 100_000
+)
+
+// WithPendingTTL bounds how long a resource's pending state (e.g. a pod
+// that was created but never seen to be scheduled or deleted) is kept
+// before it is evicted. The default is 1 hour.
+func WithPendingTTL(d time.Duration) Option {
+	return func(e *Exporter) {
+		e.pendingTTL = d
+	}
+}
+
+// WithMaxPending bounds the number of pending resource states kept at
+// once, evicting the least-recently-used entry once the limit is reached.
+// The default is 100,000.
+func WithMaxPending(n int) Option {
+	return func(e *Exporter) {
+		e.maxPending = n
+	}
+}
+
+const (
+	defaultMaxAnomalyTuples = 50_000
+	defaultAnomalyThreshold = 3.0
+)
+
+// WithMaxAnomalyTuples bounds the number of distinct (user, verb, resource)
+// tuples tracked for rate-limit and anomaly metrics at once, evicting the
+// least-recently-used tuple once the limit is reached. The default is
+// 50,000.
+func WithMaxAnomalyTuples(n int) Option {
+	return func(e *Exporter) {
+		e.maxAnomalyTuples = n
+	}
+}
+
+// WithAnomalyThreshold sets how many standard deviations a tuple's request
+// rate must be from its baseline before it counts towards
+// audit_anomaly_detected_total. The default is 3.
+func WithAnomalyThreshold(z float64) Option {
+	return func(e *Exporter) {
+		e.anomalyThreshold = z
+	}
+}
+
 func NewExporter(opts ...Option) *Exporter {
 	e := &Exporter{
-		podCreationTimes:      map[target]*time.Time{},
-		batchJobCreationTimes: map[target]*time.Time{},
+		replaySpeed:      1,
+		pendingTTL:       defaultPendingTTL,
+		maxPending:       defaultMaxPending,
+		resourceHandlers: map[gvk]ResourceHandler{},
+		maxAnomalyTuples: defaultMaxAnomalyTuples,
+		anomalyThreshold: defaultAnomalyThreshold,
 	}
+	e.setClock(realClock{})
+	e.sinks = append(e.sinks, &prometheusSink{e: e})
+	e.registerBuiltinHandlers()
 
 	for _, opt := range opts {
 		opt(e)
 	}
 
+	e.pending = newPendingCache(e.pendingTTL, e.maxPending, exporterClock{p: e})
+	go e.runPendingSweep()
+
+	e.anomaly = newAnomalyTracker(e.maxAnomalyTuples)
+
 	return e
 }
 
 type Exporter struct {
 	file   string
 	offset int64
+	inode  uint64
 
 	clusterLabel string
 	replay       bool
-	timeDiff     time.Duration
+	replaySpeed  float64
+	replayStart  time.Time
+	replayClock  *virtualClock
+	clockRef     atomic.Pointer[clockHolder]
+
+	sinks      []Sink
+	dispatchMu sync.Mutex
+
+	resourceHandlers map[gvk]ResourceHandler
+	pending          *pendingCache
+	pendingTTL       time.Duration
+	maxPending       int
+
+	anomaly          *anomalyTracker
+	maxAnomalyTuples int
+	anomalyThreshold float64
+}
+
+// runPendingSweep periodically reclaims pending resource states whose TTL
+// has elapsed, so that objects never seen again (e.g. a dropped delete
+// event) don't sit in memory until something happens to look them up.
+func (p *Exporter) runPendingSweep() {
+	interval := p.pendingTTL / 4
+	if interval < time.Minute {
+		interval = time.Minute
+	}
 
-	podCreationTimes      map[target]*time.Time
-	batchJobCreationTimes map[target]*time.Time
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		p.pending.sweepExpired()
+	}
 }
 
 func ListenAndServe(addr string) error {
@@ -73,6 +182,10 @@ func ListenAndServe(addr string) error {
 
 // Run handles audit log file changes
 func (p *Exporter) Run() {
+	if p.replay {
+		p.replayRotatedArchives(p.file)
+	}
+
 	ticker := time.NewTicker(time.Second)
 	defer ticker.Stop()
 
@@ -89,13 +202,25 @@ func (p *Exporter) handleFileEvent(path string) {
 	}
 }
 
-// processFileUpdate reads new log entries
+// processFileUpdate reads new log entries, draining the previous file to
+// EOF first if path was rotated onto a new inode since the last read.
 func (p *Exporter) processFileUpdate(path string) error {
 	fileInfo, err := os.Stat(path)
 	if err != nil {
 		return fmt.Errorf("failed to stat file: %w", err)
 	}
 
+	if inode, ok := fileInode(fileInfo); ok {
+		if p.inode != 0 && inode != p.inode {
+			slog.Info("Audit log rotated, draining previous file", "cluster", p.clusterLabel)
+			if err := p.drainRotated(path, p.inode, p.offset); err != nil {
+				slog.Error("Failed to drain rotated audit log", "cluster", p.clusterLabel, "error", err)
+			}
+			p.offset = 0
+		}
+		p.inode = inode
+	}
+
 	if size := fileInfo.Size(); size < p.offset {
 		slog.Info("Log file truncated, resetting offset", "cluster", p.clusterLabel)
 		p.offset = 0
@@ -120,62 +245,87 @@ func (p *Exporter) processFileUpdate(path string) error {
 	}()
 
 	reader := bufio.NewReaderSize(file, 1<<20) // 1MB buffer
+	n, err := p.consumeEvents(reader)
+	p.offset += n
+	return err
+}
+
+// consumeEvents reads newline-delimited audit events from reader until EOF
+// and feeds each into the sinks, pacing them in replay mode. It returns the
+// number of bytes consumed so callers can advance their own offset, if any.
+func (p *Exporter) consumeEvents(reader *bufio.Reader) (int64, error) {
+	var consumed int64
 	for {
-		err := p.skipNull(reader)
-		if err != err {
-			return err
+		skipped, err := skipNull(reader)
+		consumed += skipped
+		if err != nil {
+			return consumed, err
 		}
 
 		line, err := reader.ReadSlice('\n')
 		if err != nil {
 			if err == io.EOF || err == io.ErrUnexpectedEOF {
-				return nil
+				return consumed, nil
 			}
-			return fmt.Errorf("read error: %w", err)
+			return consumed, fmt.Errorf("read error: %w", err)
 		}
 
 		// This means that we have mislocated the read and can no longer continue execution
 		if !bytes.HasPrefix(line, []byte{'{'}) || !bytes.HasSuffix(line, []byte{'}', '\n'}) {
-			return fmt.Errorf("malformed log entry: %q", line)
+			return consumed, fmt.Errorf("malformed log entry: %q", line)
 		}
 
 		var event auditv1.Event
 		if err := json.Unmarshal(line, &event); err != nil {
-			return fmt.Errorf("json decode error: %w", err)
+			return consumed, fmt.Errorf("json decode error: %w", err)
 		}
 
 		if p.replay {
-			if p.timeDiff == 0 {
-				p.timeDiff = time.Since(event.StageTimestamp.Time)
-			} else {
-				// Simulation has been collected to EOF
-				if time.Since(event.StageTimestamp.Time) < p.timeDiff {
-					return nil
-				}
-			}
+			p.pace(event)
 		}
 
-		p.updateMetrics(p.clusterLabel, event)
-		p.offset += int64(len(line))
+		p.consumeSinks(p.clusterLabel, event)
+		consumed += int64(len(line))
+	}
+}
+
+// pace sleeps, in replay mode, until event's simulated wall-clock time.
+func (p *Exporter) pace(event auditv1.Event) {
+	if p.replayClock == nil {
+		eventStart := p.replayStart
+		if eventStart.IsZero() {
+			eventStart = event.StageTimestamp.Time
+		}
+		p.replayClock = &virtualClock{
+			wallStart:  time.Now(),
+			eventStart: eventStart,
+			speed:      p.replaySpeed,
+		}
+		p.setClock(p.replayClock)
+	}
+
+	if d := time.Until(p.replayClock.wallTimeFor(event.StageTimestamp.Time)); d > 0 {
+		time.Sleep(d)
 	}
 }
 
-func (p *Exporter) skipNull(reader *bufio.Reader) error {
+func skipNull(reader *bufio.Reader) (int64, error) {
+	var n int64
 	for {
 		peek, err := reader.Peek(1)
 		if err != nil {
 			if err == io.EOF {
-				return nil
+				return n, nil
 			}
-			return fmt.Errorf("peek error: %w", err)
+			return n, fmt.Errorf("peek error: %w", err)
 		}
 		if peek[0] != 0 {
 			break
 		}
 		if _, err := reader.ReadByte(); err != nil {
-			return fmt.Errorf("read null byte error: %w", err)
+			return n, fmt.Errorf("read null byte error: %w", err)
 		}
-		p.offset++
+		n++
 	}
-	return nil
+	return n, nil
 }
@@ -1,8 +1,6 @@
 package exporter
 
 import (
-	"encoding/json"
-	"log/slog"
 	"strconv"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -34,6 +32,43 @@ var (
 		Help:    "Time from job creation to complete condition in seconds",
 		Buckets: prometheus.ExponentialBuckets(1, 2, 12),
 	}, []string{"cluster", "namespace", "user"})
+
+	resourceCreationReadyLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "resource_creation_ready_latency_seconds",
+		Help:    "Duration from resource creation to ready in seconds, by kind",
+		Buckets: prometheus.ExponentialBuckets(1, 2, 12),
+	}, []string{"cluster", "namespace", "user", "kind"})
+
+	resourceRolloutDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "resource_rollout_duration_seconds",
+		Help:    "Duration of a resource's rollout to ready in seconds, by kind",
+		Buckets: prometheus.ExponentialBuckets(1, 2, 12),
+	}, []string{"cluster", "namespace", "user", "kind"})
+
+	resourceDeletedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "resource_deleted_total",
+		Help: "Total number of resources deleted, by kind",
+	}, []string{"cluster", "namespace", "user", "kind"})
+
+	pendingEvictedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "pending_evicted_total",
+		Help: "Total number of pending resource-state entries evicted before a matching event was seen, by reason",
+	}, []string{"reason"})
+
+	requestsPerMinute = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "api_requests_per_minute",
+		Help: "Requests in the trailing 60 seconds, by user, verb and resource",
+	}, []string{"cluster", "user", "verb", "resource"})
+
+	requestAnomalyScore = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "api_request_anomaly_score",
+		Help: "Standard deviations the current request rate is from its EWMA baseline, by user, verb and resource",
+	}, []string{"cluster", "user", "verb", "resource"})
+
+	anomalyDetectedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "audit_anomaly_detected_total",
+		Help: "Total number of times a user/verb/resource's request rate crossed the anomaly threshold",
+	}, []string{"cluster", "user", "verb", "resource"})
 )
 
 func init() {
@@ -42,6 +77,13 @@ func init() {
 		podSchedulingLatency,
 		podDeletedTotal,
 		batchJobCompleteLatency,
+		resourceCreationReadyLatency,
+		resourceRolloutDuration,
+		resourceDeletedTotal,
+		pendingEvictedTotal,
+		requestsPerMinute,
+		requestAnomalyScore,
+		anomalyDetectedTotal,
 	)
 }
 
@@ -58,125 +100,21 @@ func (p *Exporter) updateMetrics(clusterLabel string, event auditv1.Event) {
 	}
 
 	if event.Stage == auditv1.StageResponseComplete {
+		user := extractUserAgent(event.UserAgent)
+		resource := extractResourceName(event)
+
 		labels := []string{
 			clusterLabel,
 			ns,
-			extractUserAgent(event.UserAgent),
+			user,
 			event.Verb,
-			extractResourceName(event),
+			resource,
 			strconv.Itoa(int(event.ResponseStatus.Code)),
 		}
 		apiRequests.WithLabelValues(labels...).Inc()
-	}
 
-	if event.ObjectRef != nil {
-		switch event.ObjectRef.Resource {
-		case "pods":
-			if event.ObjectRef.Subresource == "binding" && event.Verb == "create" {
-				target := buildTarget(event.ObjectRef)
-				createTime, exists := p.podCreationTimes[target]
-				if !exists {
-					// Kueue's audit events may create pod/binding events before pod creation events
-					user := extractUserAgent(event.UserAgent)
-					podSchedulingLatency.WithLabelValues(
-						clusterLabel,
-						ns,
-						user,
-					).Observe(0)
-					p.podCreationTimes[target] = nil
-					return
-				}
-
-				if createTime == nil {
-					return
-				}
-				latency := event.StageTimestamp.Sub(*createTime).Seconds()
-
-				user := extractUserAgent(event.UserAgent)
-				podSchedulingLatency.WithLabelValues(
-					clusterLabel,
-					ns,
-					user,
-				).Observe(latency)
-				p.podCreationTimes[target] = nil
-
-			} else {
-				if event.Verb == "create" {
-					var pod Pod
-					err := json.Unmarshal(event.ResponseObject.Raw, &pod)
-					if err != nil {
-						slog.Error("failed to unmarshal", "err", err)
-						return
-					}
-
-					target := target{
-						Name:      pod.Metadata.Name,
-						Namespace: pod.Metadata.Namespace,
-					}
-					if pod.Spec.NodeName == "" {
-						p.podCreationTimes[target] = &event.StageTimestamp.Time
-					} else {
-						p.podCreationTimes[target] = nil
-					}
-				} else if event.Verb == "delete" {
-					delete(p.podCreationTimes, buildTarget(event.ObjectRef))
-
-					if event.ResponseObject != nil {
-						var pod Pod
-						if err := json.Unmarshal(event.ResponseObject.Raw, &pod); err != nil {
-							slog.Error("failed to unmarshal pod during delete", "err", err)
-							return
-						}
-
-						user := extractUserAgent(event.UserAgent)
-						podDeletedTotal.WithLabelValues(
-							clusterLabel,
-							ns,
-							user,
-							pod.Status.Phase,
-						).Inc()
-					}
-				}
-			}
-
-		case "jobs":
-			if event.Verb == "create" && event.ResponseObject != nil {
-				var job BatchJob
-				err := json.Unmarshal(event.ResponseObject.Raw, &job)
-				if err != nil {
-					slog.Error("failed to unmarshal", "err", err)
-					return
-				}
-
-				target := target{
-					Name:      job.Metadata.Name,
-					Namespace: job.Metadata.Namespace,
-				}
-				p.batchJobCreationTimes[target] = &event.StageTimestamp.Time
-			} else if event.Verb == "delete" {
-				target := buildTarget(event.ObjectRef)
-				delete(p.batchJobCreationTimes, target)
-			} else {
-				target := buildTarget(event.ObjectRef)
-				if createTime, ok := p.batchJobCreationTimes[target]; ok && createTime != nil && event.ResponseObject != nil {
-					var job BatchJob
-					err := json.Unmarshal(event.ResponseObject.Raw, &job)
-					if err != nil {
-						slog.Error("failed to unmarshal job", "err", err)
-						return
-					}
-					if job.Status.IsCompleted() {
-						latency := event.StageTimestamp.Sub(job.Metadata.CreationTimestamp).Seconds()
-						user := extractUserAgent(event.UserAgent)
-						batchJobCompleteLatency.WithLabelValues(
-							clusterLabel,
-							ns,
-							user,
-						).Observe(latency)
-						p.batchJobCreationTimes[target] = nil
-					}
-				}
-			}
-		}
+		p.recordAnomaly(clusterLabel, user, event.Verb, resource, event.StageTimestamp.Time)
 	}
+
+	p.dispatchResourceEvent(clusterLabel, ns, event)
 }
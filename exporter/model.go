@@ -23,6 +23,7 @@ type PodSpec struct {
 }
 
 type PodStatus struct {
+	Phase     string      `json:"phase"`
 	Condition []Condition `json:"conditions"`
 }
 
@@ -69,3 +70,74 @@ func (b BatchJobStatus) IsCompleted() bool {
 	}
 	return false
 }
+
+// ReplicaSet is the common shape of Deployment and StatefulSet status
+// reporting, which both track a desired replica count against how many
+// have rolled out and become ready.
+type ReplicaSet struct {
+	Metadata Metadata         `json:"metadata"`
+	Spec     ReplicaSetSpec   `json:"spec"`
+	Status   ReplicaSetStatus `json:"status"`
+}
+
+type ReplicaSetSpec struct {
+	Replicas int32 `json:"replicas"`
+}
+
+type ReplicaSetStatus struct {
+	Replicas        int32 `json:"replicas"`
+	ReadyReplicas   int32 `json:"readyReplicas"`
+	UpdatedReplicas int32 `json:"updatedReplicas"`
+}
+
+// RolloutComplete reports whether every desired replica has been updated
+// to the latest spec and is ready.
+func (s ReplicaSetStatus) RolloutComplete(desired int32) bool {
+	return desired > 0 && s.UpdatedReplicas >= desired && s.ReadyReplicas >= desired
+}
+
+type DaemonSet struct {
+	Metadata Metadata        `json:"metadata"`
+	Status   DaemonSetStatus `json:"status"`
+}
+
+type DaemonSetStatus struct {
+	DesiredNumberScheduled int32 `json:"desiredNumberScheduled"`
+	NumberReady            int32 `json:"numberReady"`
+}
+
+// RolloutComplete reports whether every scheduled daemon pod is ready.
+func (s DaemonSetStatus) RolloutComplete() bool {
+	return s.DesiredNumberScheduled > 0 && s.NumberReady >= s.DesiredNumberScheduled
+}
+
+type CronJob struct {
+	Metadata Metadata      `json:"metadata"`
+	Status   CronJobStatus `json:"status"`
+}
+
+type CronJobStatus struct {
+	LastScheduleTime *time.Time `json:"lastScheduleTime"`
+}
+
+// Workload is a Kueue Workload, which admits queued jobs onto the cluster
+// once quota becomes available.
+type Workload struct {
+	Metadata Metadata       `json:"metadata"`
+	Status   WorkloadStatus `json:"status"`
+}
+
+type WorkloadStatus struct {
+	Conditions []Condition `json:"conditions"`
+}
+
+// HasCondition reports whether the status carries a condition of the given
+// type in the True state.
+func (s WorkloadStatus) HasCondition(t string) bool {
+	for _, c := range s.Conditions {
+		if c.Type == t && c.Status == "True" {
+			return true
+		}
+	}
+	return false
+}
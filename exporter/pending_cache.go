@@ -0,0 +1,134 @@
+package exporter
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// cacheKey identifies a pending object across every tracked resource kind.
+type cacheKey struct {
+	GVK    gvk
+	Target target
+}
+
+type pendingEntry struct {
+	key        cacheKey
+	state      *ResourceState
+	insertedAt time.Time
+}
+
+// pendingCache is a TTL- and size-bounded store for ResourceState, replacing
+// the exporter's earlier raw per-kind maps. Without a bound, entries for
+// objects whose delete event is missing, dropped, or rotated out of the
+// audit log are never reclaimed; a long-running exporter watching a
+// high-churn cluster would otherwise grow without limit.
+type pendingCache struct {
+	ttl     time.Duration
+	maxSize int
+	clock   clock
+
+	mu    sync.Mutex
+	ll    *list.List
+	items map[cacheKey]*list.Element
+}
+
+func newPendingCache(ttl time.Duration, maxSize int, clk clock) *pendingCache {
+	return &pendingCache{
+		ttl:     ttl,
+		maxSize: maxSize,
+		clock:   clk,
+		ll:      list.New(),
+		items:   map[cacheKey]*list.Element{},
+	}
+}
+
+// get returns the state for key and marks it most-recently-used. An entry
+// whose TTL has elapsed is evicted and reported as not found.
+func (c *pendingCache) get(key cacheKey) (*ResourceState, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.getLocked(key)
+}
+
+// getLocked is get's body, reusable by callers that already hold c.mu so
+// the existence check, any eviction, and an insert can happen as one
+// atomic operation instead of being split across separately locked calls.
+func (c *pendingCache) getLocked(key cacheKey) (*ResourceState, bool) {
+	elem, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	entry := elem.Value.(*pendingEntry)
+	if c.clock.Now().Sub(entry.insertedAt) > c.ttl {
+		c.evictLocked(elem, "ttl")
+		return nil, false
+	}
+
+	c.ll.MoveToFront(elem)
+	return entry.state, true
+}
+
+// getOrCreate returns the existing state for key, or inserts and returns a
+// freshly created one. Inserting past maxSize evicts the least-recently-used
+// entry first. The whole check-then-act sequence runs under a single lock
+// so two concurrent callers for the same key can't both observe "not
+// found" and both insert, which would otherwise leave a dangling list
+// element that desyncs c.items from c.ll.
+func (c *pendingCache) getOrCreate(key cacheKey) (state *ResourceState, existed bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if state, ok := c.getLocked(key); ok {
+		return state, true
+	}
+
+	if c.maxSize > 0 && len(c.items) >= c.maxSize {
+		if oldest := c.ll.Back(); oldest != nil {
+			c.evictLocked(oldest, "size")
+		}
+	}
+
+	state = &ResourceState{}
+	elem := c.ll.PushFront(&pendingEntry{key: key, state: state, insertedAt: c.clock.Now()})
+	c.items[key] = elem
+	return state, false
+}
+
+func (c *pendingCache) delete(key cacheKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.items[key]; ok {
+		c.ll.Remove(elem)
+		delete(c.items, key)
+	}
+}
+
+// evictLocked removes elem and records why. Callers must hold c.mu.
+func (c *pendingCache) evictLocked(elem *list.Element, reason string) {
+	entry := elem.Value.(*pendingEntry)
+	c.ll.Remove(elem)
+	delete(c.items, entry.key)
+	pendingEvictedTotal.WithLabelValues(reason).Inc()
+}
+
+// sweepExpired removes every entry whose TTL has elapsed. It runs on a
+// timer so that objects which are never looked up again, e.g. a pod whose
+// delete event was dropped, are still reclaimed rather than only evicted
+// lazily on access.
+func (c *pendingCache) sweepExpired() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.clock.Now()
+	for key, elem := range c.items {
+		if now.Sub(elem.Value.(*pendingEntry).insertedAt) > c.ttl {
+			c.ll.Remove(elem)
+			delete(c.items, key)
+			pendingEvictedTotal.WithLabelValues("ttl").Inc()
+		}
+	}
+}
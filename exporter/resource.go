@@ -0,0 +1,117 @@
+package exporter
+
+import (
+	"time"
+
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+)
+
+// gvk identifies the Kubernetes resource a ResourceHandler manages. Audit
+// events identify objects by their plural resource name rather than Kind,
+// so this is really a GroupResource, but it plays the same role as a GVK
+// would for routing events to a handler.
+type gvk struct {
+	Group    string
+	Resource string
+}
+
+func gvkFromRef(ref *auditv1.ObjectReference) gvk {
+	if ref == nil {
+		return gvk{}
+	}
+	return gvk{Group: ref.APIGroup, Resource: ref.Resource}
+}
+
+// ResourceState is the per-object bookkeeping kept between the event that
+// creates an object and the event that finishes tracking it, whether
+// that's becoming ready or being deleted.
+type ResourceState struct {
+	CreatedAt time.Time
+	Ready     bool
+}
+
+// ResourceHandler implements the create/update/delete lifecycle for one
+// Kubernetes resource kind, translating audit events into metrics. Handlers
+// are looked up by gvk and share a common state registry keyed by target,
+// so they only need to decide when an object becomes ready and when to
+// emit metrics, not how pending objects are tracked.
+type ResourceHandler interface {
+	// GVK identifies the group/resource this handler manages, e.g.
+	// {Group: "apps", Resource: "deployments"}.
+	GVK() gvk
+
+	// OnCreate is called for every plain create event (no subresource) on
+	// an object of this kind.
+	OnCreate(clusterLabel, ns string, event auditv1.Event, state *ResourceState)
+	// OnUpdate is called for every other non-delete event on an object,
+	// including subresource updates such as status or binding writes that
+	// signal readiness, until its state is marked Ready. It is also called
+	// for objects whose create event was never seen.
+	OnUpdate(clusterLabel, ns string, event auditv1.Event, state *ResourceState)
+	// OnDelete is called when the object is deleted; the registry removes
+	// its state immediately afterward.
+	OnDelete(clusterLabel, ns string, event auditv1.Event, state *ResourceState)
+}
+
+// registerHandler adds h to the registry, keyed by its GVK.
+func (p *Exporter) registerHandler(h ResourceHandler) {
+	if p.resourceHandlers == nil {
+		p.resourceHandlers = map[gvk]ResourceHandler{}
+	}
+	p.resourceHandlers[h.GVK()] = h
+}
+
+// dispatchResourceEvent routes an event to the handler registered for its
+// GVK, if any, maintaining that handler's per-target state in the bounded
+// pending cache.
+func (p *Exporter) dispatchResourceEvent(clusterLabel, ns string, event auditv1.Event) {
+	if event.ObjectRef == nil {
+		return
+	}
+
+	k := gvkFromRef(event.ObjectRef)
+	handler, ok := p.resourceHandlers[k]
+	if !ok {
+		return
+	}
+
+	key := cacheKey{GVK: k, Target: buildTarget(event.ObjectRef)}
+
+	if event.Verb == "delete" {
+		// OnDelete's counters are derived from the delete event itself, not
+		// from the cached state, so it fires whether or not the object was
+		// being tracked - otherwise an object created before the exporter
+		// started watching, or whose pending entry was already evicted,
+		// would never be counted as deleted.
+		state, existed := p.pending.get(key)
+		if !existed {
+			state = &ResourceState{}
+		}
+		handler.OnDelete(clusterLabel, ns, event, state)
+		if existed {
+			p.pending.delete(key)
+		}
+		return
+	}
+
+	// A plain create (no subresource) starts tracking a new object;
+	// anything else - status/binding/scale subresource writes, patches,
+	// updates - is treated as progress on an object already being tracked.
+	// Both branches use getOrCreate: a subresource event can arrive before
+	// its object's own create event (Kueue's pod/binding events do this),
+	// so handlers see a state with a zero CreatedAt rather than no state
+	// at all.
+	if event.Verb == "create" && event.ObjectRef.Subresource == "" {
+		state, existed := p.pending.getOrCreate(key)
+		if !existed {
+			state.CreatedAt = event.StageTimestamp.Time
+		}
+		handler.OnCreate(clusterLabel, ns, event, state)
+		return
+	}
+
+	state, _ := p.pending.getOrCreate(key)
+	if !state.Ready {
+		handler.OnUpdate(clusterLabel, ns, event, state)
+	}
+}
@@ -0,0 +1,234 @@
+package exporter
+
+import (
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+)
+
+// registerBuiltinHandlers installs the handlers shipped with the exporter.
+func (p *Exporter) registerBuiltinHandlers() {
+	p.registerHandler(podHandler{})
+	p.registerHandler(jobHandler{})
+	p.registerHandler(replicaRolloutHandler{resource: "deployments"})
+	p.registerHandler(replicaRolloutHandler{resource: "statefulsets"})
+	p.registerHandler(daemonSetHandler{})
+	p.registerHandler(cronJobHandler{})
+	p.registerHandler(workloadHandler{})
+}
+
+// podHandler tracks pod-create-to-scheduled latency and deletion counts.
+// It preserves the exporter's original pod metrics and quirks, notably that
+// Kueue may emit a pod/binding event before the pod creation event is seen.
+type podHandler struct{}
+
+func (podHandler) GVK() gvk { return gvk{Resource: "pods"} }
+
+func (podHandler) OnCreate(clusterLabel, ns string, event auditv1.Event, state *ResourceState) {
+	if event.ResponseObject == nil {
+		return
+	}
+	var pod Pod
+	if err := json.Unmarshal(event.ResponseObject.Raw, &pod); err != nil {
+		slog.Error("failed to unmarshal pod", "err", err)
+		return
+	}
+	if pod.Spec.NodeName != "" {
+		// Already scheduled at creation time, so there is no binding event
+		// to wait for.
+		state.Ready = true
+	}
+}
+
+func (podHandler) OnUpdate(clusterLabel, ns string, event auditv1.Event, state *ResourceState) {
+	if event.ObjectRef.Subresource != "binding" || event.Verb != "create" {
+		return
+	}
+
+	user := extractUserAgent(event.UserAgent)
+	if state.CreatedAt.IsZero() {
+		// Kueue's audit events may emit pod/binding events before the pod
+		// creation event.
+		podSchedulingLatency.WithLabelValues(clusterLabel, ns, user).Observe(0)
+	} else {
+		latency := event.StageTimestamp.Sub(state.CreatedAt).Seconds()
+		podSchedulingLatency.WithLabelValues(clusterLabel, ns, user).Observe(latency)
+	}
+	state.Ready = true
+}
+
+func (podHandler) OnDelete(clusterLabel, ns string, event auditv1.Event, state *ResourceState) {
+	if event.ResponseObject == nil {
+		return
+	}
+	var pod Pod
+	if err := json.Unmarshal(event.ResponseObject.Raw, &pod); err != nil {
+		slog.Error("failed to unmarshal pod during delete", "err", err)
+		return
+	}
+
+	user := extractUserAgent(event.UserAgent)
+	podDeletedTotal.WithLabelValues(clusterLabel, ns, user, pod.Status.Phase).Inc()
+}
+
+// jobHandler tracks batch Job completion latency, measured from the job's
+// own metadata creation timestamp rather than the audit event that first
+// created it.
+type jobHandler struct{}
+
+func (jobHandler) GVK() gvk { return gvk{Group: "batch", Resource: "jobs"} }
+
+func (jobHandler) OnCreate(clusterLabel, ns string, event auditv1.Event, state *ResourceState) {}
+
+func (jobHandler) OnUpdate(clusterLabel, ns string, event auditv1.Event, state *ResourceState) {
+	if event.ResponseObject == nil {
+		return
+	}
+	var job BatchJob
+	if err := json.Unmarshal(event.ResponseObject.Raw, &job); err != nil {
+		slog.Error("failed to unmarshal job", "err", err)
+		return
+	}
+	if !job.Status.IsCompleted() {
+		return
+	}
+
+	latency := event.StageTimestamp.Sub(job.Metadata.CreationTimestamp).Seconds()
+	user := extractUserAgent(event.UserAgent)
+	batchJobCompleteLatency.WithLabelValues(clusterLabel, ns, user).Observe(latency)
+	state.Ready = true
+}
+
+func (jobHandler) OnDelete(clusterLabel, ns string, event auditv1.Event, state *ResourceState) {}
+
+// replicaRolloutHandler is shared between Deployments and StatefulSets,
+// which both roll out a desired number of replicas.
+type replicaRolloutHandler struct {
+	resource string
+}
+
+func (h replicaRolloutHandler) GVK() gvk { return gvk{Group: "apps", Resource: h.resource} }
+
+func (h replicaRolloutHandler) OnCreate(clusterLabel, ns string, event auditv1.Event, state *ResourceState) {
+}
+
+func (h replicaRolloutHandler) OnUpdate(clusterLabel, ns string, event auditv1.Event, state *ResourceState) {
+	if event.ResponseObject == nil {
+		return
+	}
+	var rs ReplicaSet
+	if err := json.Unmarshal(event.ResponseObject.Raw, &rs); err != nil {
+		slog.Error("failed to unmarshal "+h.resource, "err", err)
+		return
+	}
+	if !rs.Status.RolloutComplete(rs.Spec.Replicas) || state.CreatedAt.IsZero() {
+		return
+	}
+
+	observeResourceReady(clusterLabel, ns, h.resource, extractUserAgent(event.UserAgent), event.StageTimestamp.Sub(state.CreatedAt))
+	state.Ready = true
+}
+
+func (h replicaRolloutHandler) OnDelete(clusterLabel, ns string, event auditv1.Event, state *ResourceState) {
+	resourceDeletedTotal.WithLabelValues(clusterLabel, ns, extractUserAgent(event.UserAgent), h.resource).Inc()
+}
+
+// daemonSetHandler rolls out to every eligible node rather than a fixed
+// replica count, so readiness is derived from NumberReady/DesiredNumberScheduled.
+type daemonSetHandler struct{}
+
+func (daemonSetHandler) GVK() gvk { return gvk{Group: "apps", Resource: "daemonsets"} }
+
+func (daemonSetHandler) OnCreate(clusterLabel, ns string, event auditv1.Event, state *ResourceState) {
+}
+
+func (daemonSetHandler) OnUpdate(clusterLabel, ns string, event auditv1.Event, state *ResourceState) {
+	if event.ResponseObject == nil {
+		return
+	}
+	var ds DaemonSet
+	if err := json.Unmarshal(event.ResponseObject.Raw, &ds); err != nil {
+		slog.Error("failed to unmarshal daemonset", "err", err)
+		return
+	}
+	if !ds.Status.RolloutComplete() || state.CreatedAt.IsZero() {
+		return
+	}
+
+	observeResourceReady(clusterLabel, ns, "daemonsets", extractUserAgent(event.UserAgent), event.StageTimestamp.Sub(state.CreatedAt))
+	state.Ready = true
+}
+
+func (daemonSetHandler) OnDelete(clusterLabel, ns string, event auditv1.Event, state *ResourceState) {
+	resourceDeletedTotal.WithLabelValues(clusterLabel, ns, extractUserAgent(event.UserAgent), "daemonsets").Inc()
+}
+
+// cronJobHandler treats a CronJob as "ready" once it has scheduled its
+// first run; it has no separate rollout phase.
+type cronJobHandler struct{}
+
+func (cronJobHandler) GVK() gvk { return gvk{Group: "batch", Resource: "cronjobs"} }
+
+func (cronJobHandler) OnCreate(clusterLabel, ns string, event auditv1.Event, state *ResourceState) {
+}
+
+func (cronJobHandler) OnUpdate(clusterLabel, ns string, event auditv1.Event, state *ResourceState) {
+	if event.ResponseObject == nil {
+		return
+	}
+	var cj CronJob
+	if err := json.Unmarshal(event.ResponseObject.Raw, &cj); err != nil {
+		slog.Error("failed to unmarshal cronjob", "err", err)
+		return
+	}
+	if cj.Status.LastScheduleTime == nil || state.CreatedAt.IsZero() {
+		return
+	}
+
+	observeResourceReady(clusterLabel, ns, "cronjobs", extractUserAgent(event.UserAgent), event.StageTimestamp.Sub(state.CreatedAt))
+	state.Ready = true
+}
+
+func (cronJobHandler) OnDelete(clusterLabel, ns string, event auditv1.Event, state *ResourceState) {
+	resourceDeletedTotal.WithLabelValues(clusterLabel, ns, extractUserAgent(event.UserAgent), "cronjobs").Inc()
+}
+
+// workloadHandler tracks Kueue Workload admission latency: the time from
+// the Workload being created to Kueue admitting it onto the cluster.
+type workloadHandler struct{}
+
+func (workloadHandler) GVK() gvk { return gvk{Group: "kueue.x-k8s.io", Resource: "workloads"} }
+
+func (workloadHandler) OnCreate(clusterLabel, ns string, event auditv1.Event, state *ResourceState) {
+}
+
+func (workloadHandler) OnUpdate(clusterLabel, ns string, event auditv1.Event, state *ResourceState) {
+	if event.ResponseObject == nil {
+		return
+	}
+	var w Workload
+	if err := json.Unmarshal(event.ResponseObject.Raw, &w); err != nil {
+		slog.Error("failed to unmarshal workload", "err", err)
+		return
+	}
+	if !w.Status.HasCondition("Admitted") || state.CreatedAt.IsZero() {
+		return
+	}
+
+	observeResourceReady(clusterLabel, ns, "workloads", extractUserAgent(event.UserAgent), event.StageTimestamp.Sub(state.CreatedAt))
+	state.Ready = true
+}
+
+func (workloadHandler) OnDelete(clusterLabel, ns string, event auditv1.Event, state *ResourceState) {
+	resourceDeletedTotal.WithLabelValues(clusterLabel, ns, extractUserAgent(event.UserAgent), "workloads").Inc()
+}
+
+// observeResourceReady records both the creation-to-ready latency and the
+// rollout duration for a kind, since for most built-in handlers becoming
+// ready is the only rollout phase there is.
+func observeResourceReady(clusterLabel, ns, kind, user string, latency time.Duration) {
+	resourceCreationReadyLatency.WithLabelValues(clusterLabel, ns, user, kind).Observe(latency.Seconds())
+	resourceRolloutDuration.WithLabelValues(clusterLabel, ns, user, kind).Observe(latency.Seconds())
+}
@@ -0,0 +1,64 @@
+package exporter
+
+import (
+	"log/slog"
+
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+)
+
+// Sink receives parsed audit events in addition to (or instead of) the
+// built-in Prometheus metrics. It lets operators fan events out to other
+// observability backends, such as an OTel collector, a JSON log, or a
+// webhook, without coupling the file-tailing loop to any one of them.
+type Sink interface {
+	// Consume is called once per parsed audit event, unfiltered - including
+	// non-2xx responses and stages other than ResponseComplete. The
+	// built-in Prometheus sink applies its own filtering internally
+	// (successful requests only, and only ResponseComplete-stage ones count
+	// towards api_requests_total, see updateMetrics); other sinks see
+	// everything and must filter themselves if they want the same behavior.
+	Consume(clusterLabel string, e auditv1.Event) error
+
+	// Close releases any resources held by the sink, e.g. network
+	// connections or open files. It is called when the exporter is done
+	// processing, which today is effectively never since Run loops forever.
+	Close() error
+}
+
+// WithSink registers an additional Sink that every processed audit event is
+// forwarded to. The built-in Prometheus sink is always installed first and
+// cannot be removed; WithSink only appends.
+func WithSink(s Sink) Option {
+	return func(e *Exporter) {
+		e.sinks = append(e.sinks, s)
+	}
+}
+
+// consumeSinks fans an event out to every configured sink, logging (but not
+// stopping on) individual sink errors so that one broken destination can't
+// block the others or the file-tailing loop itself.
+//
+// Dispatch is serialized by p.dispatchMu. File-tailing mode only ever has
+// one goroutine calling this, but webhook mode hands each incoming request
+// to its own goroutine (net/http's default), and they would otherwise race
+// on state that's only safe for a single writer: the pending cache's
+// ResourceState fields, which handlers mutate outside the cache's lock.
+func (p *Exporter) consumeSinks(clusterLabel string, e auditv1.Event) {
+	p.dispatchMu.Lock()
+	defer p.dispatchMu.Unlock()
+
+	for _, sink := range p.sinks {
+		if err := sink.Consume(clusterLabel, e); err != nil {
+			slog.Error("Sink failed to consume event", "cluster", clusterLabel, "error", err)
+		}
+	}
+}
+
+// closeSinks closes every configured sink, logging any errors encountered.
+func (p *Exporter) closeSinks() {
+	for _, sink := range p.sinks {
+		if err := sink.Close(); err != nil {
+			slog.Error("Sink failed to close", "cluster", p.clusterLabel, "error", err)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package exporter
+
+import (
+	"encoding/json"
+	"io"
+
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+)
+
+// jsonSink writes each consumed event as a single line of NDJSON to an
+// underlying writer, wrapped with the cluster label it was tagged with.
+type jsonSink struct {
+	w   io.Writer
+	enc *json.Encoder
+}
+
+// NewJSONSink returns a Sink that writes one NDJSON object per event to w,
+// suitable for piping into log aggregation or `jq`-based ad-hoc analysis.
+func NewJSONSink(w io.Writer) Sink {
+	return &jsonSink{
+		w:   w,
+		enc: json.NewEncoder(w),
+	}
+}
+
+type jsonSinkEvent struct {
+	Cluster string        `json:"cluster"`
+	Event   auditv1.Event `json:"event"`
+}
+
+func (s *jsonSink) Consume(clusterLabel string, e auditv1.Event) error {
+	return s.enc.Encode(jsonSinkEvent{
+		Cluster: clusterLabel,
+		Event:   e,
+	})
+}
+
+func (s *jsonSink) Close() error {
+	if c, ok := s.w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
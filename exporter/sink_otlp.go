@@ -0,0 +1,76 @@
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+)
+
+// otlpSink mirrors the Prometheus sink's api_requests_total counter, but
+// pushes it to an OTel collector over OTLP/gRPC instead of serving a /metrics
+// endpoint. This lets clusters that already ship metrics via an OTel
+// pipeline pick up audit-derived signals without scraping this process.
+type otlpSink struct {
+	provider *sdkmetric.MeterProvider
+	requests metric.Int64Counter
+}
+
+// NewOTLPSink dials the OTLP/gRPC metrics endpoint at target (e.g.
+// "otel-collector:4317") and returns a Sink that records one counter,
+// api_requests_total, mirroring the Prometheus metric of the same name.
+func NewOTLPSink(ctx context.Context, target string) (Sink, error) {
+	exp, err := otlpmetricgrpc.New(ctx, otlpmetricgrpc.WithEndpoint(target), otlpmetricgrpc.WithInsecure())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP metric exporter: %w", err)
+	}
+
+	provider := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exp)),
+	)
+
+	meter := provider.Meter("kube-apiserver-audit-exporter")
+	requests, err := meter.Int64Counter("api_requests_total",
+		metric.WithDescription("Total number of API requests to the scheduler"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create api_requests_total counter: %w", err)
+	}
+
+	return &otlpSink{
+		provider: provider,
+		requests: requests,
+	}, nil
+}
+
+func (s *otlpSink) Consume(clusterLabel string, e auditv1.Event) error {
+	if e.Stage != auditv1.StageResponseComplete ||
+		e.ResponseStatus == nil || e.ResponseStatus.Code < 200 || e.ResponseStatus.Code >= 300 {
+		return nil
+	}
+
+	var ns string
+	if e.ObjectRef != nil {
+		ns = e.ObjectRef.Namespace
+	}
+
+	s.requests.Add(context.Background(), 1,
+		metric.WithAttributes(
+			attribute.String("cluster", clusterLabel),
+			attribute.String("namespace", ns),
+			attribute.String("user", extractUserAgent(e.UserAgent)),
+			attribute.String("verb", e.Verb),
+			attribute.String("resource", extractResourceName(e)),
+			attribute.String("code", strconv.Itoa(int(e.ResponseStatus.Code))),
+		),
+	)
+	return nil
+}
+
+func (s *otlpSink) Close() error {
+	return s.provider.Shutdown(context.Background())
+}
@@ -0,0 +1,22 @@
+package exporter
+
+import (
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+)
+
+// prometheusSink is the exporter's original behavior: it feeds events into
+// updateMetrics, which maintains the Prometheus registry exposed on
+// /metrics. It is always installed as the first sink so existing behavior
+// is unchanged when no other sinks are configured.
+type prometheusSink struct {
+	e *Exporter
+}
+
+func (s *prometheusSink) Consume(clusterLabel string, e auditv1.Event) error {
+	s.e.updateMetrics(clusterLabel, e)
+	return nil
+}
+
+func (s *prometheusSink) Close() error {
+	return nil
+}
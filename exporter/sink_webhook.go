@@ -0,0 +1,56 @@
+package exporter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+)
+
+// webhookSink POSTs each event as JSON to a configured URL. It is the
+// building block for bridging audit events into systems that accept
+// webhook pushes, e.g. a Kafka HTTP bridge or a generic event collector.
+type webhookSink struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookSink returns a Sink that POSTs a JSON-encoded
+// {cluster, event} payload to url for every consumed event.
+func NewWebhookSink(url string) Sink {
+	return &webhookSink{
+		url: url,
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+	}
+}
+
+func (s *webhookSink) Consume(clusterLabel string, e auditv1.Event) error {
+	body, err := json.Marshal(jsonSinkEvent{
+		Cluster: clusterLabel,
+		Event:   e,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	resp, err := s.client.Post(s.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to post event: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (s *webhookSink) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
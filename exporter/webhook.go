@@ -0,0 +1,109 @@
+package exporter
+
+import (
+	"crypto/subtle"
+	"crypto/tls"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	auditv1 "k8s.io/apiserver/pkg/apis/audit/v1"
+)
+
+// maxWebhookBodyBytes caps the size of an incoming webhook payload so that
+// a misbehaving or malicious client can't exhaust memory with an
+// unbounded request body.
+const maxWebhookBodyBytes = 64 << 20 // 64MB
+
+// WebhookOption configures a webhook listener started by ServeWebhook.
+type WebhookOption func(c *webhookConfig)
+
+type webhookConfig struct {
+	bearerToken string
+	tlsConfig   *tls.Config
+}
+
+// WithWebhookBearerToken requires incoming requests to carry
+// "Authorization: Bearer <token>" matching token, rejecting all others.
+func WithWebhookBearerToken(token string) WebhookOption {
+	return func(c *webhookConfig) {
+		c.bearerToken = token
+	}
+}
+
+// WithWebhookTLSConfig serves the webhook over TLS using cfg, e.g. to
+// require and verify client certificates (mTLS) for apiservers configured
+// with a client certificate rather than a bearer token.
+func WithWebhookTLSConfig(cfg *tls.Config) WebhookOption {
+	return func(c *webhookConfig) {
+		c.tlsConfig = cfg
+	}
+}
+
+// ServeWebhook starts an HTTP server on addr that accepts audit.k8s.io/v1
+// EventList batches POSTed by a Kubernetes dynamic audit webhook backend,
+// feeding each event straight into the exporter's sinks. It blocks until
+// the server stops, like http.ListenAndServe.
+func (p *Exporter) ServeWebhook(addr string, opts ...WebhookOption) error {
+	cfg := &webhookConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", p.handleWebhook(cfg))
+
+	server := &http.Server{
+		Addr:    addr,
+		Handler: mux,
+	}
+
+	if cfg.tlsConfig != nil {
+		server.TLSConfig = cfg.tlsConfig
+		slog.Info("Webhook server started", "cluster", p.clusterLabel, "address", addr, "tls", true)
+		return server.ListenAndServeTLS("", "")
+	}
+
+	slog.Info("Webhook server started", "cluster", p.clusterLabel, "address", addr, "tls", false)
+	return server.ListenAndServe()
+}
+
+func (p *Exporter) handleWebhook(cfg *webhookConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		if cfg.bearerToken != "" {
+			want := []byte("Bearer " + cfg.bearerToken)
+			got := []byte(r.Header.Get("Authorization"))
+			if len(got) != len(want) || subtle.ConstantTimeCompare(got, want) != 1 {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+		}
+
+		if ct := r.Header.Get("Content-Type"); ct != "" && !strings.HasPrefix(ct, "application/json") {
+			http.Error(w, "unsupported content type", http.StatusUnsupportedMediaType)
+			return
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, maxWebhookBodyBytes)
+		defer r.Body.Close()
+
+		var list auditv1.EventList
+		if err := json.NewDecoder(r.Body).Decode(&list); err != nil {
+			slog.Error("Failed to decode webhook payload", "cluster", p.clusterLabel, "error", err)
+			http.Error(w, "invalid payload", http.StatusBadRequest)
+			return
+		}
+
+		for _, event := range list.Items {
+			p.consumeSinks(p.clusterLabel, event)
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}
+}